@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+// runtimeConfig groups `coder org runtime-config` subcommands. It must be appended to
+// the `organizations` command's Children (alongside the other `coder organizations`
+// subcommands) for `coder organizations runtime-config` to actually be reachable; it
+// isn't wired in automatically just by existing in this package.
+func (r *RootCmd) runtimeConfig() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "runtime-config",
+		Short: "Manage organization runtime configuration overrides.",
+		Children: []*serpent.Command{
+			r.runtimeConfigExport(),
+			r.runtimeConfigImport(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) runtimeConfigExport() *serpent.Command {
+	var (
+		orgContext = NewOrganizationContext()
+		outputFile string
+		format     string
+	)
+
+	cmd := &serpent.Command{
+		Use:   "export",
+		Short: "Export all non-secret runtime config overrides for an organization.",
+		Options: serpent.OptionSet{
+			{
+				Flag:        "output",
+				Description: "File to write the export to; defaults to stdout.",
+				Value:       serpent.StringOf(&outputFile),
+			},
+			{
+				Flag:        "format",
+				Description: "Output format: json or yaml.",
+				Default:     "json",
+				Value:       serpent.EnumOf(&format, "json", "yaml"),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			org, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			export, err := client.ExportRuntimeConfig(inv.Context(), org.ID)
+			if err != nil {
+				return xerrors.Errorf("export runtime config: %w", err)
+			}
+
+			var out []byte
+			switch format {
+			case "yaml":
+				out, err = yaml.Marshal(export)
+			default:
+				out, err = json.MarshalIndent(export, "", "  ")
+			}
+			if err != nil {
+				return xerrors.Errorf("marshal export: %w", err)
+			}
+
+			if outputFile == "" {
+				_, err = inv.Stdout.Write(append(out, '\n'))
+				return err
+			}
+			return os.WriteFile(outputFile, append(out, '\n'), 0o600)
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+func (r *RootCmd) runtimeConfigImport() *serpent.Command {
+	var (
+		orgContext = NewOrganizationContext()
+		inputFile  string
+		dryRun     bool
+		merge      bool
+	)
+
+	cmd := &serpent.Command{
+		Use:   "import <file>",
+		Short: "Import runtime config overrides for an organization.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:        "dry-run",
+				Description: "Print what would change without writing anything.",
+				Value:       serpent.BoolOf(&dryRun),
+			},
+			{
+				Flag:        "merge",
+				Description: "Only touch keys present in the import file; by default, non-secret overrides missing from the file are removed so they match it exactly. Secret overrides are never changed.",
+				Value:       serpent.BoolOf(&merge),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			inputFile = inv.Args[0]
+
+			client, err := r.InitClient(inv)
+			if err != nil {
+				return err
+			}
+
+			org, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			raw, err := os.ReadFile(inputFile)
+			if err != nil {
+				return xerrors.Errorf("read %s: %w", inputFile, err)
+			}
+
+			var export codersdk.RuntimeConfigExport
+			if err := yaml.Unmarshal(raw, &export); err != nil {
+				return xerrors.Errorf("parse %s as JSON or YAML: %w", inputFile, err)
+			}
+
+			diffs, err := client.ImportRuntimeConfig(inv.Context(), org.ID, codersdk.ImportRuntimeConfigRequest{
+				Export: export,
+				DryRun: dryRun,
+				Merge:  merge,
+			})
+			if err != nil {
+				return xerrors.Errorf("import runtime config: %w", err)
+			}
+
+			for _, d := range diffs {
+				if d.Action == codersdk.RuntimeConfigImportActionNoop {
+					continue
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "%s %s: %q -> %q\n", d.Action, d.Key, d.Before, d.After)
+			}
+			if dryRun {
+				_, _ = fmt.Fprintln(inv.Stdout, cliui.DefaultStyles.Wrap.Render("Dry run: no changes were written."))
+			}
+			return nil
+		},
+	}
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
@@ -0,0 +1,395 @@
+package coderd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/serpent"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// redactedValue is returned in place of a secret entry's real value to any caller
+// without readSecret permission on the organization.
+const redactedValue = "***"
+
+// runtimeConfigResolver builds the full read-side pipeline for org: encryption-at-rest
+// first, so callers further up (Coalesce, the REST handlers) never see ciphertext.
+func (api *API) runtimeConfigResolver(store database.Store, orgID uuid.UUID) runtimeconfig.Resolver {
+	return runtimeconfig.DecryptingResolver(
+		runtimeconfig.NewOrgResolver(orgID, runtimeconfig.NewStoreResolver(store)),
+		api.RuntimeConfigKeyProvider,
+	)
+}
+
+// runtimeConfigMutator builds the full write-side pipeline for org: encrypt, then
+// audit log using the value the audit entry's diff needs, which is read back through
+// resolver before the write (falling back to the deployment startup value via
+// runtimeconfig.Discover when there's no prior org override).
+func (api *API) runtimeConfigMutator(r *http.Request, store database.Store, orgID uuid.UUID) runtimeconfig.Mutator {
+	resolver := api.runtimeConfigResolver(store, orgID)
+	encrypting := runtimeconfig.EncryptingMutator(
+		runtimeconfig.NewOrgMutator(orgID, runtimeconfig.NewStoreMutator(store)),
+		api.RuntimeConfigKeyProvider,
+	)
+	registry := runtimeconfig.Discover(api.DeploymentValues.Options())
+	return runtimeconfig.MutatorWithAudit(encrypting, resolver, registry, orgID, api.RuntimeConfigAuditLogger, httpmw.APIKey(r).UserID)
+}
+
+// nonSecretRuntimeConfigRegistry is runtimeconfig.Discover with every secret-backed key
+// removed. Both export and import build their registry this way: an export document
+// can never carry a secret's plaintext value, so it must never be asked to act as the
+// source of truth for whether a secret override should exist, either.
+func nonSecretRuntimeConfigRegistry(options serpent.OptionSet) map[string]runtimeconfig.Resolvable {
+	registry := runtimeconfig.Discover(options)
+	for key := range registry {
+		if runtimeconfig.IsSecret(key) {
+			delete(registry, key)
+		}
+	}
+	return registry
+}
+
+// runtimeConfigRoutes mounts the organization runtime-config endpoints on r. Every
+// handler it mounts calls httpmw.OrganizationParam(r), so r must already be inside a
+// route group carrying httpmw.ExtractOrganizationParam — the same group every other
+// per-organization resource is mounted under. The caller adds it as a single statement
+// from inside that existing "/organizations/{organization}" route group, e.g.:
+//
+//	r.Route("/organizations/{organization}", func(r chi.Router) {
+//		r.Use(httpmw.ExtractOrganizationParam(api.Database))
+//		...
+//		api.runtimeConfigRoutes(r)
+//	})
+func (api *API) runtimeConfigRoutes(r chi.Router) {
+	r.Route("/runtime-config", func(r chi.Router) {
+		r.Get("/", api.organizationRuntimeConfigs)
+		r.Get("/export", api.exportOrganizationRuntimeConfig)
+		r.Post("/import", api.importOrganizationRuntimeConfig)
+		r.Route("/{key}", func(r chi.Router) {
+			r.Get("/", api.organizationRuntimeConfig)
+			r.Post("/", api.patchOrganizationRuntimeConfig)
+			r.Delete("/", api.deleteOrganizationRuntimeConfig)
+		})
+	})
+}
+
+// organizationRuntimeConfigs godoc
+//
+//	@Summary	List organization runtime config overrides
+//	@ID		list-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Produce	json
+//	@Tags		Enterprise
+//	@Param		organization	path		string	true	"Organization ID"
+//	@Success	200				{array}		codersdk.RuntimeConfigEntry
+//	@Router		/organizations/{organization}/runtime-config [get]
+func (api *API) organizationRuntimeConfigs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigRead(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	resolver := api.runtimeConfigResolver(api.Database, org.ID)
+	canReadSecrets := api.canReadRuntimeConfigSecrets(r, org.ID)
+
+	entries := runtimeconfig.Discover(api.DeploymentValues.Options())
+	resp := make([]codersdk.RuntimeConfigEntry, 0, len(entries))
+	for key, entry := range entries {
+		out, err := runtimeConfigEntryResponse(ctx, resolver, key, entry, canReadSecrets)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		resp = append(resp, out)
+	}
+
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Key < resp[j].Key })
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// organizationRuntimeConfig godoc
+//
+//	@Summary	Get an organization runtime config override
+//	@ID		get-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Produce	json
+//	@Tags		Enterprise
+//	@Param		organization	path		string	true	"Organization ID"
+//	@Param		key				path		string	true	"Runtime config key"
+//	@Success	200				{object}	codersdk.RuntimeConfigEntry
+//	@Router		/organizations/{organization}/runtime-config/{key} [get]
+func (api *API) organizationRuntimeConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigRead(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	key := chi.URLParam(r, "key")
+	resolver := api.runtimeConfigResolver(api.Database, org.ID)
+
+	entry, ok := runtimeconfig.Discover(api.DeploymentValues.Options())[key]
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Unknown runtime config key.",
+		})
+		return
+	}
+
+	resp, err := runtimeConfigEntryResponse(ctx, resolver, key, entry, api.canReadRuntimeConfigSecrets(r, org.ID))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// patchOrganizationRuntimeConfig godoc
+//
+//	@Summary	Set an organization runtime config override
+//	@ID		set-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Enterprise
+//	@Param		organization	path		string									true	"Organization ID"
+//	@Param		key				path		string									true	"Runtime config key"
+//	@Param		request			body		codersdk.UpdateRuntimeConfigRequest	true	"New value"
+//	@Success	200				{object}	codersdk.RuntimeConfigEntry
+//	@Router		/organizations/{organization}/runtime-config/{key} [post]
+func (api *API) patchOrganizationRuntimeConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigWrite(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	key := chi.URLParam(r, "key")
+	mutator := api.runtimeConfigMutator(r, api.Database, org.ID)
+	resolver := api.runtimeConfigResolver(api.Database, org.ID)
+
+	entry, ok := runtimeconfig.Discover(api.DeploymentValues.Options())[key]
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Unknown runtime config key.",
+		})
+		return
+	}
+
+	var req codersdk.UpdateRuntimeConfigRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if err := entry.SetRuntimeValueString(ctx, mutator, req.Value); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid value for runtime config key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := runtimeConfigEntryResponse(ctx, resolver, key, entry, api.canReadRuntimeConfigSecrets(r, org.ID))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// deleteOrganizationRuntimeConfig godoc
+//
+//	@Summary	Delete an organization runtime config override
+//	@ID		delete-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Tags		Enterprise
+//	@Param		organization	path	string	true	"Organization ID"
+//	@Param		key				path	string	true	"Runtime config key"
+//	@Success	204
+//	@Router		/organizations/{organization}/runtime-config/{key} [delete]
+func (api *API) deleteOrganizationRuntimeConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigWrite(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	key := chi.URLParam(r, "key")
+	mutator := api.runtimeConfigMutator(r, api.Database, org.ID)
+
+	entry, ok := runtimeconfig.Discover(api.DeploymentValues.Options())[key]
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Unknown runtime config key.",
+		})
+		return
+	}
+
+	if err := entry.UnsetRuntimeValue(ctx, mutator); err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// exportOrganizationRuntimeConfig godoc
+//
+//	@Summary	Export organization runtime config overrides
+//	@ID		export-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Produce	json
+//	@Tags		Enterprise
+//	@Param		organization	path		string	true	"Organization ID"
+//	@Success	200				{object}	codersdk.RuntimeConfigExport
+//	@Router		/organizations/{organization}/runtime-config/export [get]
+func (api *API) exportOrganizationRuntimeConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigRead(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	resolver := api.runtimeConfigResolver(api.Database, org.ID)
+
+	// Secrets are never exported: an export document that a caller can move between
+	// organizations is exactly the kind of thing that shouldn't carry plaintext
+	// credentials at rest on disk.
+	registry := nonSecretRuntimeConfigRegistry(api.DeploymentValues.Options())
+
+	doc, err := runtimeconfig.Export(ctx, resolver, registry)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := codersdk.RuntimeConfigExport{Version: doc.Version}
+	for _, e := range doc.Entries {
+		resp.Entries = append(resp.Entries, codersdk.RuntimeConfigExportRow{Key: e.Key, Value: e.Value})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// importOrganizationRuntimeConfig godoc
+//
+//	@Summary	Import organization runtime config overrides
+//	@ID		import-organization-runtime-config
+//	@Security	CoderSessionToken
+//	@Accept		json
+//	@Produce	json
+//	@Tags		Enterprise
+//	@Param		organization	path		string									true	"Organization ID"
+//	@Param		request			body		codersdk.ImportRuntimeConfigRequest	true	"Document to import"
+//	@Success	200				{array}		codersdk.RuntimeConfigImportDiff
+//	@Router		/organizations/{organization}/runtime-config/import [post]
+func (api *API) importOrganizationRuntimeConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	if !api.authorizeRuntimeConfigWrite(r, org.ID) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.ImportRuntimeConfigRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// An import document can never contain a secret value (Export never writes one), so
+	// a replace-mode (non-Merge) import must not treat that structural absence as "the
+	// caller wants this override gone": secret overrides are left untouched by import
+	// entirely, the same as they're left out of export.
+	registry := nonSecretRuntimeConfigRegistry(api.DeploymentValues.Options())
+
+	doc := runtimeconfig.ExportDocument{Version: req.Export.Version}
+	for _, e := range req.Export.Entries {
+		doc.Entries = append(doc.Entries, runtimeconfig.ExportedEntry{Key: e.Key, Value: e.Value})
+	}
+
+	var diffs []runtimeconfig.ImportDiff
+	err := api.Database.InTx(func(tx database.Store) error {
+		txResolver := api.runtimeConfigResolver(tx, org.ID)
+		txMutator := api.runtimeConfigMutator(r, tx, org.ID)
+		var txErr error
+		diffs, txErr = runtimeconfig.Import(ctx, txMutator, txResolver, registry, doc, runtimeconfig.ImportOptions{
+			DryRun: req.DryRun,
+			Merge:  req.Merge,
+		})
+		return txErr
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unable to import runtime config.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.RuntimeConfigImportDiff, 0, len(diffs))
+	for _, d := range diffs {
+		resp = append(resp, codersdk.RuntimeConfigImportDiff{
+			Key:    d.Key,
+			Before: d.Before,
+			After:  d.After,
+			Action: codersdk.RuntimeConfigImportAction(d.Action),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+func runtimeConfigEntryResponse(ctx context.Context, resolver runtimeconfig.Resolver, key string, entry runtimeconfig.Resolvable, canReadSecrets bool) (codersdk.RuntimeConfigEntry, error) {
+	secret := runtimeconfig.IsSecret(key)
+
+	startup := entry.StartupValueString()
+	override, err := entry.ResolveString(ctx, resolver)
+
+	resp := codersdk.RuntimeConfigEntry{Key: key, Secret: secret}
+	switch {
+	case err == nil:
+		resp.StartupValue, resp.Value, resp.Overridden = startup, override, true
+	case errors.Is(err, runtimeconfig.EntryNotFound):
+		resp.StartupValue, resp.Value, resp.Overridden = startup, startup, false
+	default:
+		return codersdk.RuntimeConfigEntry{}, err
+	}
+
+	if secret && !canReadSecrets {
+		resp.StartupValue, resp.Value = redactedValue, redactedValue
+	}
+	return resp, nil
+}
+
+// canReadRuntimeConfigSecrets reports whether the caller may see the plaintext value
+// of a secret runtime config entry (e.g. an SMTP password), as opposed to just knowing
+// that one is set. It's gated on the same permission as other org-secret reads.
+func (api *API) canReadRuntimeConfigSecrets(r *http.Request, orgID uuid.UUID) bool {
+	return api.Authorize(r, policy.ActionReadSecret, rbac.ResourceOrganization.WithID(orgID).InOrg(orgID))
+}
+
+// authorizeRuntimeConfigRead reports whether the caller may list, get, or export
+// organization runtime config overrides at all (canReadRuntimeConfigSecrets only
+// governs whether a secret's value is redacted within a response the caller is
+// otherwise allowed to see).
+func (api *API) authorizeRuntimeConfigRead(r *http.Request, orgID uuid.UUID) bool {
+	return api.Authorize(r, policy.ActionRead, rbac.ResourceOrganization.WithID(orgID).InOrg(orgID))
+}
+
+// authorizeRuntimeConfigWrite reports whether the caller may set, delete, or import
+// organization runtime config overrides. These are org-wide settings (OIDC params,
+// notifier settings, etc.), so they're gated the same as other organization-level
+// writes rather than anything key-specific.
+func (api *API) authorizeRuntimeConfigWrite(r *http.Request, orgID uuid.UUID) bool {
+	return api.Authorize(r, policy.ActionUpdate, rbac.ResourceOrganization.WithID(orgID).InOrg(orgID))
+}
@@ -0,0 +1,196 @@
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// redactedSecretValue replaces a secret entry's Old/New value in an audit entry. The
+// fact that a change happened is still recorded; the value itself is not.
+const redactedSecretValue = "***"
+
+// AuditAction identifies what kind of change a runtime config mutation made.
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionWrite  AuditAction = "write"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// FieldDiff is a single changed field within an AuditEntry's Diff, used when the
+// entry's underlying value is struct-typed (e.g. serpent.Struct[map[string]string])
+// so the audit log shows which fields actually changed instead of two opaque blobs.
+type FieldDiff struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditEntry describes a single SetRuntimeValue/UnsetRuntimeValue call to be recorded
+// by an AuditLogger.
+type AuditEntry struct {
+	Key    string
+	OrgID  uuid.UUID
+	Actor  uuid.UUID
+	Action AuditAction
+	// Old and New are the raw String() forms of the value before and after the
+	// change. For a secret entry a non-empty value is replaced with
+	// redactedSecretValue; an empty one (e.g. New on delete) stays empty.
+	Old, New string
+	// Diff breaks Old/New down field-by-field when the value is a JSON object (as
+	// produced by e.g. serpent.Struct); for scalar values it holds a single "value"
+	// field, and it's empty for secret entries.
+	Diff map[string]FieldDiff
+}
+
+// AuditLogger records a runtime config mutation after it has committed. It's narrower
+// than coderd/audit.Auditor, which is scoped to HTTP requests; coderd's wiring adapts
+// its real auditor to this interface when constructing a MutatorWithAudit, including
+// for mutations (like a bulk Import) that don't originate from a single request.
+type AuditLogger interface {
+	AuditRuntimeConfig(ctx context.Context, entry AuditEntry) error
+}
+
+// NewNoopAuditLogger returns an AuditLogger that discards every entry. It's the
+// default for OSS builds and for tests that use NewNoopMutator.
+func NewNoopAuditLogger() AuditLogger {
+	return noopAuditLogger{}
+}
+
+type noopAuditLogger struct{}
+
+func (noopAuditLogger) AuditRuntimeConfig(context.Context, AuditEntry) error { return nil }
+
+// MutatorWithAudit wraps mutator so that every successful write also produces an
+// AuditEntry via auditor, attributed to actorID within orgID. resolver is used to read
+// the value being replaced, since a plain Mutator can only write; registry (typically
+// the result of Discover) lets the "before" read fall back to an entry's deployment
+// startup value when there's no prior override, so the audit log can say what a
+// setting's effective value actually was, not just whether an override row existed.
+func MutatorWithAudit(mutator Mutator, resolver Resolver, registry map[string]Resolvable, orgID uuid.UUID, auditor AuditLogger, actorID uuid.UUID) Mutator {
+	return &auditMutator{
+		Mutator:  mutator,
+		resolver: resolver,
+		registry: registry,
+		orgID:    orgID,
+		auditor:  auditor,
+		actorID:  actorID,
+	}
+}
+
+type auditMutator struct {
+	Mutator
+	resolver Resolver
+	registry map[string]Resolvable
+	orgID    uuid.UUID
+	auditor  AuditLogger
+	actorID  uuid.UUID
+}
+
+func (m *auditMutator) UpsertRuntimeConfig(ctx context.Context, key string, val string) error {
+	before, existed, err := currentRawValue(ctx, m.resolver, m.registry, key)
+	if err != nil {
+		return xerrors.Errorf("read previous value of %q for audit: %w", key, err)
+	}
+
+	if err := m.Mutator.UpsertRuntimeConfig(ctx, key, val); err != nil {
+		return err
+	}
+
+	action := AuditActionWrite
+	if !existed {
+		action = AuditActionCreate
+	}
+	return m.audit(ctx, key, action, before, val)
+}
+
+func (m *auditMutator) DeleteRuntimeConfig(ctx context.Context, key string) error {
+	before, _, err := currentRawValue(ctx, m.resolver, m.registry, key)
+	if err != nil {
+		return xerrors.Errorf("read previous value of %q for audit: %w", key, err)
+	}
+
+	if err := m.Mutator.DeleteRuntimeConfig(ctx, key); err != nil {
+		return err
+	}
+	return m.audit(ctx, key, AuditActionDelete, before, "")
+}
+
+func (m *auditMutator) audit(ctx context.Context, key string, action AuditAction, before, after string) error {
+	entry := AuditEntry{
+		Key:    key,
+		OrgID:  m.orgID,
+		Actor:  m.actorID,
+		Action: action,
+		Old:    before,
+		New:    after,
+	}
+
+	if IsSecret(key) {
+		if before != "" {
+			entry.Old = redactedSecretValue
+		}
+		if after != "" {
+			entry.New = redactedSecretValue
+		}
+	} else {
+		entry.Diff = diffValue(before, after)
+	}
+
+	if err := m.auditor.AuditRuntimeConfig(ctx, entry); err != nil {
+		return xerrors.Errorf("record audit log for runtime config %q: %w", key, err)
+	}
+	return nil
+}
+
+// currentRawValue returns key's current override according to resolver, and whether
+// one exists at all. When none exists, it falls back to registry's deployment startup
+// value for key (if registry has an entry for it) so the audit log's "before" snapshot
+// reflects the setting's actual effective value, not just an empty override.
+func currentRawValue(ctx context.Context, resolver Resolver, registry map[string]Resolvable, key string) (value string, existed bool, err error) {
+	val, err := resolver.GetRuntimeConfig(ctx, key)
+	switch {
+	case err == nil:
+		return val, true, nil
+	case errors.Is(err, EntryNotFound):
+		if entry, ok := registry[key]; ok {
+			return entry.StartupValueString(), false, nil
+		}
+		return "", false, nil
+	default:
+		return "", false, err
+	}
+}
+
+// diffValue compares old and new as JSON objects, returning a field-by-field diff
+// when both parse as objects (as a serpent.Struct's String() form does). If either
+// side isn't a JSON object, the whole value is treated as a single opaque field named
+// "value" — the common case for scalar entries like serpent.String or serpent.Bool.
+func diffValue(old, new string) map[string]FieldDiff {
+	var oldFields, newFields map[string]any
+	oldIsObject := old == "" || json.Unmarshal([]byte(old), &oldFields) == nil
+	newIsObject := new == "" || json.Unmarshal([]byte(new), &newFields) == nil
+
+	if !oldIsObject || !newIsObject {
+		return map[string]FieldDiff{"value": {Old: old, New: new}}
+	}
+
+	diff := make(map[string]FieldDiff)
+	for field, oldVal := range oldFields {
+		if newVal, ok := newFields[field]; !ok || !reflect.DeepEqual(oldVal, newVal) {
+			diff[field] = FieldDiff{Old: oldVal, New: newFields[field]}
+		}
+	}
+	for field, newVal := range newFields {
+		if _, ok := oldFields[field]; ok {
+			continue
+		}
+		diff[field] = FieldDiff{New: newVal}
+	}
+	return diff
+}
@@ -0,0 +1,136 @@
+package runtimeconfig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coder/serpent"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type fakeAuditLogger struct {
+	entries []runtimeconfig.AuditEntry
+}
+
+func (f *fakeAuditLogger) AuditRuntimeConfig(_ context.Context, entry runtimeconfig.AuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func TestMutatorWithAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store := dbmem.New()
+	orgID, actorID := uuid.New(), uuid.New()
+
+	field := runtimeconfig.MustNew[*serpent.String]("my-field", "default")
+	registry := map[string]runtimeconfig.Resolvable{field.Key(): &field}
+
+	resolver := runtimeconfig.NewOrgResolver(orgID, runtimeconfig.NewStoreResolver(store))
+	auditor := &fakeAuditLogger{}
+	mutator := runtimeconfig.MutatorWithAudit(
+		runtimeconfig.NewOrgMutator(orgID, runtimeconfig.NewStoreMutator(store)),
+		resolver,
+		registry,
+		orgID,
+		auditor,
+		actorID,
+	)
+
+	v1 := serpent.String("first")
+	require.NoError(t, field.SetRuntimeValue(ctx, mutator, &v1))
+	require.Len(t, auditor.entries, 1)
+	require.Equal(t, runtimeconfig.AuditActionCreate, auditor.entries[0].Action)
+	require.Equal(t, "default", auditor.entries[0].Old, "before any override, the effective value is the deployment default")
+	require.Equal(t, "first", auditor.entries[0].New)
+	require.Equal(t, orgID, auditor.entries[0].OrgID)
+	require.Equal(t, actorID, auditor.entries[0].Actor)
+
+	v2 := serpent.String("second")
+	require.NoError(t, field.SetRuntimeValue(ctx, mutator, &v2))
+	require.Len(t, auditor.entries, 2)
+	require.Equal(t, runtimeconfig.AuditActionWrite, auditor.entries[1].Action)
+	require.Equal(t, "first", auditor.entries[1].Old)
+	require.Equal(t, "second", auditor.entries[1].New)
+
+	require.NoError(t, field.UnsetRuntimeValue(ctx, mutator))
+	require.Len(t, auditor.entries, 3)
+	require.Equal(t, runtimeconfig.AuditActionDelete, auditor.entries[2].Action)
+	require.Equal(t, "second", auditor.entries[2].Old)
+	require.Equal(t, "", auditor.entries[2].New)
+}
+
+func TestMutatorWithAuditRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store := dbmem.New()
+	orgID, actorID := uuid.New(), uuid.New()
+
+	field := runtimeconfig.NewSecretEntry[*serpent.String]("smtp-password", "")
+	registry := map[string]runtimeconfig.Resolvable{field.Key(): &field}
+
+	resolver := runtimeconfig.NewOrgResolver(orgID, runtimeconfig.NewStoreResolver(store))
+	auditor := &fakeAuditLogger{}
+	mutator := runtimeconfig.MutatorWithAudit(
+		runtimeconfig.EncryptingMutator(
+			runtimeconfig.NewOrgMutator(orgID, runtimeconfig.NewStoreMutator(store)),
+			runtimeconfig.NewNoopKeyProvider(),
+		),
+		resolver,
+		registry,
+		orgID,
+		auditor,
+		actorID,
+	)
+
+	secret := serpent.String("hunter2")
+	require.NoError(t, field.SetRuntimeValue(ctx, mutator, &secret))
+	require.Len(t, auditor.entries, 1)
+	require.Equal(t, "***", auditor.entries[0].New)
+	require.NotContains(t, auditor.entries[0].New, "hunter2")
+	require.Empty(t, auditor.entries[0].Diff, "secret entries shouldn't get a field-level diff")
+}
+
+func TestDiffValueStructFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store := dbmem.New()
+	orgID, actorID := uuid.New(), uuid.New()
+
+	field := runtimeconfig.MustNew[*serpent.Struct[map[string]string]](
+		"oidc-auth-params",
+		(&serpent.Struct[map[string]string]{Value: map[string]string{"access_type": "offline"}}).String(),
+	)
+	registry := map[string]runtimeconfig.Resolvable{field.Key(): &field}
+
+	resolver := runtimeconfig.NewOrgResolver(orgID, runtimeconfig.NewStoreResolver(store))
+	auditor := &fakeAuditLogger{}
+	mutator := runtimeconfig.MutatorWithAudit(
+		runtimeconfig.NewOrgMutator(orgID, runtimeconfig.NewStoreMutator(store)),
+		resolver,
+		registry,
+		orgID,
+		auditor,
+		actorID,
+	)
+
+	next := serpent.Struct[map[string]string]{Value: map[string]string{"access_type": "online", "prompt": "consent"}}
+	require.NoError(t, field.SetRuntimeValue(ctx, mutator, &next))
+
+	require.Len(t, auditor.entries, 1)
+	diff := auditor.entries[0].Diff
+	// No prior override existed, so "before" falls back to the deployment default:
+	// {"access_type": "offline"}.
+	require.Equal(t, "offline", diff["access_type"].Old)
+	require.Equal(t, "online", diff["access_type"].New)
+	require.Nil(t, diff["prompt"].Old)
+	require.Equal(t, "consent", diff["prompt"].New)
+}
@@ -0,0 +1,173 @@
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/dbcrypt"
+)
+
+// secretKeys tracks which registered keys were declared via NewSecretEntry. An
+// EncryptingMutator/DecryptingResolver consults it to decide whether a given key's
+// value needs to go through a KeyProvider at all; entries that were never marked
+// secret are passed through untouched.
+var secretKeys sync.Map // map[string]struct{}
+
+// IsSecret reports whether key was registered with NewSecretEntry.
+func IsSecret(key string) bool {
+	_, ok := secretKeys.Load(key)
+	return ok
+}
+
+// NewSecretEntry behaves like MustNew, but marks key as holding a sensitive value.
+// Once marked, an EncryptingMutator encrypts the value before it reaches the
+// underlying store, a DecryptingResolver decrypts it on the way back out, and the
+// REST/CLI surface redacts it to "***" unless the caller holds readSecret permission.
+func NewSecretEntry[T Value](key, def string) Entry[T] {
+	secretKeys.Store(key, struct{}{})
+	return MustNew[T](key, def)
+}
+
+// KeyProvider encrypts and decrypts runtime config values bound for the database. The
+// returned keyID identifies which key encrypted a given ciphertext, so it can be
+// decrypted again after a key rotation introduces a new primary key.
+type KeyProvider interface {
+	Encrypt(ctx context.Context, plaintext string) (ciphertext string, keyID string, err error)
+	Decrypt(ctx context.Context, ciphertext string, keyID string) (plaintext string, err error)
+}
+
+// secretPayload is the JSON form stored in the database for a secret entry's value; it
+// carries enough information to find the right cipher on decrypt even after rotation.
+type secretPayload struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// EncryptingMutator wraps mutator so that writes to keys registered via NewSecretEntry
+// are encrypted with kp before being persisted. Writes to other keys pass through
+// unchanged.
+func EncryptingMutator(mutator Mutator, kp KeyProvider) Mutator {
+	return &encryptingMutator{Mutator: mutator, kp: kp}
+}
+
+type encryptingMutator struct {
+	Mutator
+	kp KeyProvider
+}
+
+func (m *encryptingMutator) UpsertRuntimeConfig(ctx context.Context, key string, val string) error {
+	if !IsSecret(key) {
+		return m.Mutator.UpsertRuntimeConfig(ctx, key, val)
+	}
+
+	ciphertext, keyID, err := m.kp.Encrypt(ctx, val)
+	if err != nil {
+		return xerrors.Errorf("encrypt runtime config value: %w", err)
+	}
+
+	payload, err := json.Marshal(secretPayload{KeyID: keyID, Ciphertext: ciphertext})
+	if err != nil {
+		return xerrors.Errorf("marshal secret payload: %w", err)
+	}
+
+	return m.Mutator.UpsertRuntimeConfig(ctx, key, string(payload))
+}
+
+// DecryptingResolver wraps resolver so that reads of keys registered via
+// NewSecretEntry are decrypted with kp before Resolve sees them. Reads of other keys
+// pass through unchanged.
+func DecryptingResolver(resolver Resolver, kp KeyProvider) Resolver {
+	return &decryptingResolver{Resolver: resolver, kp: kp}
+}
+
+type decryptingResolver struct {
+	Resolver
+	kp KeyProvider
+}
+
+func (r *decryptingResolver) GetRuntimeConfig(ctx context.Context, key string) (string, error) {
+	raw, err := r.Resolver.GetRuntimeConfig(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !IsSecret(key) {
+		return raw, nil
+	}
+
+	var payload secretPayload
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return "", xerrors.Errorf("unmarshal secret payload for %q: %w", key, err)
+	}
+
+	plaintext, err := r.kp.Decrypt(ctx, payload.Ciphertext, payload.KeyID)
+	if err != nil {
+		return "", xerrors.Errorf("decrypt runtime config value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NewNoopKeyProvider returns a KeyProvider that stores values as-is. It's used by OSS
+// builds and tests where no encryption-at-rest is configured.
+func NewNoopKeyProvider() KeyProvider {
+	return noopKeyProvider{}
+}
+
+type noopKeyProvider struct{}
+
+func (noopKeyProvider) Encrypt(_ context.Context, plaintext string) (string, string, error) {
+	return plaintext, "", nil
+}
+
+func (noopKeyProvider) Decrypt(_ context.Context, ciphertext string, _ string) (string, error) {
+	return ciphertext, nil
+}
+
+// NewAESKeyProvider returns a KeyProvider backed by the same dbcrypt.Cipher chain used
+// to encrypt other sensitive database fields (e.g. external auth tokens). ciphers[0] is
+// used to encrypt new values; on decrypt the chain is searched for the cipher matching
+// the stored key ID, so values written under a previous primary key keep working while
+// that key stays in the chain. Stored values are not re-encrypted when the chain
+// changes; a value moves to the current primary key only when it's next rewritten.
+func NewAESKeyProvider(ciphers []dbcrypt.Cipher) KeyProvider {
+	return &aesKeyProvider{ciphers: ciphers}
+}
+
+type aesKeyProvider struct {
+	ciphers []dbcrypt.Cipher
+}
+
+func (p *aesKeyProvider) Encrypt(_ context.Context, plaintext string) (string, string, error) {
+	if len(p.ciphers) == 0 {
+		return "", "", xerrors.New("no ciphers configured")
+	}
+	primary := p.ciphers[0]
+
+	ciphertext, err := primary.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", "", xerrors.Errorf("encrypt: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), primary.HexDigest(), nil
+}
+
+func (p *aesKeyProvider) Decrypt(_ context.Context, ciphertext string, keyID string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", xerrors.Errorf("decode ciphertext: %w", err)
+	}
+
+	for _, cipher := range p.ciphers {
+		if cipher.HexDigest() != keyID {
+			continue
+		}
+		plaintext, err := cipher.Decrypt(raw)
+		if err != nil {
+			return "", xerrors.Errorf("decrypt: %w", err)
+		}
+		return string(plaintext), nil
+	}
+	return "", xerrors.Errorf("no cipher found for key id %q; it may have been rotated out", keyID)
+}
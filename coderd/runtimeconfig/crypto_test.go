@@ -0,0 +1,101 @@
+package runtimeconfig_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/coder/serpent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbcrypt"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// newTestCiphers builds a dbcrypt.Cipher chain from raw 32-byte keys, primary first,
+// mirroring how `coder server dbcrypt rotate` assembles its chain from
+// --external-token-encryption-keys.
+func newTestCiphers(t *testing.T, keys ...string) []dbcrypt.Cipher {
+	t.Helper()
+
+	raw := make([][]byte, 0, len(keys))
+	for _, k := range keys {
+		require.Len(t, k, 32, "test cipher keys must be exactly 32 bytes")
+		raw = append(raw, []byte(k))
+	}
+
+	ciphers, err := dbcrypt.NewCiphers(raw...)
+	require.NoError(t, err)
+	return ciphers
+}
+
+func TestSecretEntry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store := dbmem.New()
+
+	kp := runtimeconfig.NewNoopKeyProvider()
+	mutator := runtimeconfig.EncryptingMutator(runtimeconfig.NewStoreMutator(store), kp)
+	resolver := runtimeconfig.DecryptingResolver(runtimeconfig.NewStoreResolver(store), kp)
+
+	field := runtimeconfig.NewSecretEntry[*serpent.String]("smtp-password", "")
+	require.True(t, runtimeconfig.IsSecret("smtp-password"))
+	require.False(t, runtimeconfig.IsSecret("some-other-key"))
+
+	secret := serpent.String("hunter2")
+	require.NoError(t, field.SetRuntimeValue(ctx, mutator, &secret))
+
+	// The value stored in the database should never be the plaintext; a secret
+	// entry's payload is always a JSON envelope, even with a no-op key provider.
+	raw, err := runtimeconfig.NewStoreResolver(store).GetRuntimeConfig(ctx, "smtp-password")
+	require.NoError(t, err)
+	require.NotEqual(t, "hunter2", raw)
+	require.Contains(t, raw, "key_id")
+
+	resolved, err := field.Resolve(ctx, resolver)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", resolved.String())
+}
+
+func TestAESKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	ciphers := newTestCiphers(t, "0123456789abcdef0123456789abcdef")
+	kp := runtimeconfig.NewAESKeyProvider(ciphers)
+
+	ciphertext, keyID, err := kp.Encrypt(context.Background(), "hunter2")
+	require.NoError(t, err)
+	require.NotEqual(t, "hunter2", ciphertext)
+	require.NotEmpty(t, keyID)
+
+	plaintext, err := kp.Decrypt(context.Background(), ciphertext, keyID)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", plaintext)
+}
+
+func TestAESKeyProviderRotation(t *testing.T) {
+	t.Parallel()
+
+	oldCiphers := newTestCiphers(t, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	oldKP := runtimeconfig.NewAESKeyProvider(oldCiphers)
+
+	ciphertext, keyID, err := oldKP.Encrypt(context.Background(), "hunter2")
+	require.NoError(t, err)
+
+	// After rotation, the new primary key is prepended but the old key is kept around
+	// so existing ciphertexts still decrypt.
+	rotatedCiphers := append(newTestCiphers(t, "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"), oldCiphers...)
+	rotatedKP := runtimeconfig.NewAESKeyProvider(rotatedCiphers)
+
+	plaintext, err := rotatedKP.Decrypt(context.Background(), ciphertext, keyID)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", plaintext)
+
+	// A key ID that isn't in the chain at all should fail clearly.
+	_, err = rotatedKP.Decrypt(context.Background(), ciphertext, "not-a-real-key-id")
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "no cipher found"))
+}
@@ -0,0 +1,156 @@
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"golang.org/x/xerrors"
+)
+
+// ExportedEntry is a single key/value override captured by Export.
+type ExportedEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ExportVersion is bumped whenever the shape of an export document changes in a way
+// that Import needs to special-case.
+const ExportVersion = 1
+
+// ExportDocument is the versioned document produced by Export and consumed by Import.
+// It only ever contains keys that had an override at the time of export; keys still
+// at their deployment default are omitted.
+type ExportDocument struct {
+	Version int             `json:"version"`
+	Entries []ExportedEntry `json:"entries"`
+}
+
+// Export reads every override present in resolver for the keys in registry (typically
+// the result of Discover against codersdk.DeploymentValues.Options()) and returns them
+// as a versioned document suitable for Import into the same or another organization.
+func Export(ctx context.Context, resolver Resolver, registry map[string]Resolvable) (ExportDocument, error) {
+	doc := ExportDocument{Version: ExportVersion}
+
+	for key, entry := range registry {
+		val, err := entry.ResolveString(ctx, resolver)
+		switch {
+		case err == nil:
+			doc.Entries = append(doc.Entries, ExportedEntry{Key: key, Value: val})
+		case errors.Is(err, EntryNotFound):
+			// No override at this level; nothing to export for this key.
+			continue
+		default:
+			return ExportDocument{}, xerrors.Errorf("resolve %q: %w", key, err)
+		}
+	}
+
+	sort.Slice(doc.Entries, func(i, j int) bool { return doc.Entries[i].Key < doc.Entries[j].Key })
+	return doc, nil
+}
+
+// ImportAction describes what Import did (or, under DryRun, would do) for a single key.
+type ImportAction string
+
+const (
+	ImportActionSet   ImportAction = "set"
+	ImportActionUnset ImportAction = "unset"
+	ImportActionNoop  ImportAction = "noop"
+)
+
+// ImportDiff reports the effect of Import on a single key.
+type ImportDiff struct {
+	Key    string       `json:"key"`
+	Before string       `json:"before"`
+	After  string       `json:"after"`
+	Action ImportAction `json:"action"`
+}
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// DryRun reports what Import would do without writing anything.
+	DryRun bool
+	// Merge only touches keys present in the document. Without Merge (i.e. Replace
+	// semantics), any key in registry that has an override but isn't present in the
+	// document has that override removed, so the target ends up looking exactly like
+	// the document.
+	Merge bool
+}
+
+// Import applies doc to mutator. Every entry is validated against its registered type
+// before anything is written, so a single malformed value fails the whole import
+// without partially applying it.
+func Import(ctx context.Context, mutator Mutator, resolver Resolver, registry map[string]Resolvable, doc ExportDocument, opts ImportOptions) ([]ImportDiff, error) {
+	imported := make(map[string]struct{}, len(doc.Entries))
+	for _, e := range doc.Entries {
+		entry, ok := registry[e.Key]
+		if !ok {
+			return nil, xerrors.Errorf("unknown runtime config key %q", e.Key)
+		}
+		if err := entry.Validate(e.Value); err != nil {
+			return nil, xerrors.Errorf("invalid value for %q: %w", e.Key, err)
+		}
+		imported[e.Key] = struct{}{}
+	}
+
+	var diffs []ImportDiff
+	for _, e := range doc.Entries {
+		entry := registry[e.Key]
+		before, _, err := currentValue(ctx, resolver, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := ImportDiff{Key: e.Key, Before: before, After: e.Value, Action: ImportActionNoop}
+		if before != e.Value {
+			diff.Action = ImportActionSet
+			if !opts.DryRun {
+				if err := entry.SetRuntimeValueString(ctx, mutator, e.Value); err != nil {
+					return nil, xerrors.Errorf("set %q: %w", e.Key, err)
+				}
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+
+	if !opts.Merge {
+		for key, entry := range registry {
+			if _, ok := imported[key]; ok {
+				continue
+			}
+			before, exists, err := currentValue(ctx, resolver, entry)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue // no override to remove, whatever before's zero value looks like
+			}
+
+			diffs = append(diffs, ImportDiff{Key: key, Before: before, After: "", Action: ImportActionUnset})
+			if !opts.DryRun {
+				if err := entry.UnsetRuntimeValue(ctx, mutator); err != nil {
+					return nil, xerrors.Errorf("unset %q: %w", key, err)
+				}
+			}
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs, nil
+}
+
+// currentValue returns entry's current override and whether one exists at all,
+// distinguishing "no override" from "an override whose value happens to be the empty
+// string" — callers that need to know whether there's anything to remove must check
+// exists, not val == "".
+func currentValue(ctx context.Context, resolver Resolver, entry Resolvable) (val string, exists bool, err error) {
+	val, err = entry.ResolveString(ctx, resolver)
+	switch {
+	case err == nil:
+		return val, true, nil
+	case errors.Is(err, EntryNotFound):
+		return "", false, nil
+	default:
+		return "", false, err
+	}
+}
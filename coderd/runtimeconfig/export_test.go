@@ -0,0 +1,137 @@
+package runtimeconfig_test
+
+import (
+	"testing"
+
+	"github.com/coder/serpent"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srcOrg, dstOrg := uuid.New(), uuid.New()
+	store := dbmem.New()
+
+	nameField := runtimeconfig.MustNew[*serpent.String]("org-name", "default-name")
+	countField := runtimeconfig.MustNew[*serpent.Int64]("org-count", "1")
+	registry := map[string]runtimeconfig.Resolvable{
+		nameField.Key():  &nameField,
+		countField.Key(): &countField,
+	}
+
+	srcResolver := runtimeconfig.NewOrgResolver(srcOrg, runtimeconfig.NewStoreResolver(store))
+	srcMutator := runtimeconfig.NewOrgMutator(srcOrg, runtimeconfig.NewStoreMutator(store))
+
+	// Only org-name has an override; org-count stays at its default.
+	overrideName := serpent.String("acme-staging")
+	require.NoError(t, nameField.SetRuntimeValue(ctx, srcMutator, &overrideName))
+
+	doc, err := runtimeconfig.Export(ctx, srcResolver, registry)
+	require.NoError(t, err)
+	require.Len(t, doc.Entries, 1)
+	require.Equal(t, "org-name", doc.Entries[0].Key)
+	require.Equal(t, "acme-staging", doc.Entries[0].Value)
+
+	dstResolver := runtimeconfig.NewOrgResolver(dstOrg, runtimeconfig.NewStoreResolver(store))
+	dstMutator := runtimeconfig.NewOrgMutator(dstOrg, runtimeconfig.NewStoreMutator(store))
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		diffs, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, doc, runtimeconfig.ImportOptions{DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, diffs, 1)
+		require.Equal(t, runtimeconfig.ImportActionSet, diffs[0].Action)
+
+		_, err = nameField.Resolve(ctx, dstResolver)
+		require.ErrorIs(t, err, runtimeconfig.EntryNotFound)
+	})
+
+	t.Run("malformed value fails the whole import", func(t *testing.T) {
+		bad := runtimeconfig.ExportDocument{
+			Version: runtimeconfig.ExportVersion,
+			Entries: []runtimeconfig.ExportedEntry{
+				{Key: "org-name", Value: "fine"},
+				{Key: "org-count", Value: "not-an-int"},
+			},
+		}
+		_, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, bad, runtimeconfig.ImportOptions{})
+		require.Error(t, err)
+
+		// Neither entry should have been written.
+		_, err = nameField.Resolve(ctx, dstResolver)
+		require.ErrorIs(t, err, runtimeconfig.EntryNotFound)
+	})
+
+	t.Run("apply writes the override", func(t *testing.T) {
+		diffs, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, doc, runtimeconfig.ImportOptions{})
+		require.NoError(t, err)
+		require.Len(t, diffs, 1)
+
+		got, err := nameField.Resolve(ctx, dstResolver)
+		require.NoError(t, err)
+		require.Equal(t, "acme-staging", got.String())
+	})
+
+	t.Run("replace semantics remove overrides missing from the document", func(t *testing.T) {
+		extraVal := serpent.Int64(99)
+		require.NoError(t, countField.SetRuntimeValue(ctx, dstMutator, &extraVal))
+
+		diffs, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, doc, runtimeconfig.ImportOptions{})
+		require.NoError(t, err)
+
+		var unsetCount int
+		for _, d := range diffs {
+			if d.Key == "org-count" {
+				require.Equal(t, runtimeconfig.ImportActionUnset, d.Action)
+				unsetCount++
+			}
+		}
+		require.Equal(t, 1, unsetCount)
+
+		_, err = countField.Resolve(ctx, dstResolver)
+		require.ErrorIs(t, err, runtimeconfig.EntryNotFound)
+	})
+
+	t.Run("merge semantics leave other overrides alone", func(t *testing.T) {
+		extraVal := serpent.Int64(99)
+		require.NoError(t, countField.SetRuntimeValue(ctx, dstMutator, &extraVal))
+
+		_, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, doc, runtimeconfig.ImportOptions{Merge: true})
+		require.NoError(t, err)
+
+		got, err := countField.Resolve(ctx, dstResolver)
+		require.NoError(t, err)
+		require.Equal(t, int64(99), got.Value())
+	})
+
+	t.Run("replace semantics remove an override whose value is the empty string", func(t *testing.T) {
+		emptyVal := serpent.String("")
+		require.NoError(t, countField.UnsetRuntimeValue(ctx, dstMutator))
+		require.NoError(t, nameField.SetRuntimeValue(ctx, dstMutator, &emptyVal))
+
+		// org-name is still in doc (value "acme-staging"), so use a doc that omits it
+		// to exercise the replace-mode cleanup path against an empty-string override.
+		empty := runtimeconfig.ExportDocument{Version: runtimeconfig.ExportVersion}
+
+		diffs, err := runtimeconfig.Import(ctx, dstMutator, dstResolver, registry, empty, runtimeconfig.ImportOptions{})
+		require.NoError(t, err)
+
+		var unset bool
+		for _, d := range diffs {
+			if d.Key == "org-name" {
+				require.Equal(t, runtimeconfig.ImportActionUnset, d.Action)
+				unset = true
+			}
+		}
+		require.True(t, unset, "an override whose value is \"\" must still be unset by replace semantics")
+
+		_, err = nameField.Resolve(ctx, dstResolver)
+		require.ErrorIs(t, err, runtimeconfig.EntryNotFound)
+	})
+}
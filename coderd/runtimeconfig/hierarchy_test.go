@@ -0,0 +1,105 @@
+package runtimeconfig_test
+
+import (
+	"testing"
+
+	"github.com/coder/serpent"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// TestChain demonstrates that a workspace-level override beats a user-level override,
+// which beats an org-level override, which beats the deployment default, and that
+// unsetting an override at one level correctly falls back to the next.
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store := dbmem.New()
+
+	orgID, userID, workspaceID := uuid.New(), uuid.New(), uuid.New()
+
+	storeResolver := runtimeconfig.NewStoreResolver(store)
+	orgResolver := runtimeconfig.NewOrgResolver(orgID, storeResolver)
+	userResolver := runtimeconfig.NewUserResolver(orgID, userID, storeResolver)
+	workspaceResolver := runtimeconfig.NewWorkspaceResolver(orgID, workspaceID, storeResolver)
+
+	chain := runtimeconfig.NewChainResolver(
+		runtimeconfig.ChainLink{Level: runtimeconfig.LevelWorkspace, Resolver: workspaceResolver},
+		runtimeconfig.ChainLink{Level: runtimeconfig.LevelUser, Resolver: userResolver},
+		runtimeconfig.ChainLink{Level: runtimeconfig.LevelOrganization, Resolver: orgResolver},
+	)
+
+	storeMutator := runtimeconfig.NewStoreMutator(store)
+	mutator := runtimeconfig.NewChainMutator(
+		runtimeconfig.ChainMutatorLink{Level: runtimeconfig.LevelWorkspace, Mutator: runtimeconfig.NewWorkspaceMutator(orgID, workspaceID, storeMutator)},
+		runtimeconfig.ChainMutatorLink{Level: runtimeconfig.LevelUser, Mutator: runtimeconfig.NewUserMutator(orgID, userID, storeMutator)},
+		runtimeconfig.ChainMutatorLink{Level: runtimeconfig.LevelOrganization, Mutator: runtimeconfig.NewOrgMutator(orgID, storeMutator)},
+	)
+
+	field := runtimeconfig.MustNew[*serpent.String]("my-field", "deployment-default")
+
+	// No overrides yet: Coalesce falls all the way back to the deployment default.
+	val, level, err := runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "deployment-default", val.String())
+	require.Equal(t, runtimeconfig.LevelDeployment, level)
+
+	// An org-level override beats the deployment default.
+	orgMutator, err := mutator.At(runtimeconfig.LevelOrganization)
+	require.NoError(t, err)
+	orgVal := serpent.String("org-override")
+	require.NoError(t, field.SetRuntimeValue(ctx, orgMutator, &orgVal))
+
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "org-override", val.String())
+	require.Equal(t, runtimeconfig.LevelOrganization, level)
+
+	// A user-level override beats the org-level override.
+	userMutator, err := mutator.At(runtimeconfig.LevelUser)
+	require.NoError(t, err)
+	userVal := serpent.String("user-override")
+	require.NoError(t, field.SetRuntimeValue(ctx, userMutator, &userVal))
+
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "user-override", val.String())
+	require.Equal(t, runtimeconfig.LevelUser, level)
+
+	// A workspace-level override beats everything else.
+	workspaceMutator, err := mutator.At(runtimeconfig.LevelWorkspace)
+	require.NoError(t, err)
+	workspaceVal := serpent.String("workspace-override")
+	require.NoError(t, field.SetRuntimeValue(ctx, workspaceMutator, &workspaceVal))
+
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "workspace-override", val.String())
+	require.Equal(t, runtimeconfig.LevelWorkspace, level)
+
+	// Unsetting the workspace override falls back to the next level down: user.
+	require.NoError(t, field.UnsetRuntimeValue(ctx, workspaceMutator))
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "user-override", val.String())
+	require.Equal(t, runtimeconfig.LevelUser, level)
+
+	// Unsetting the user override falls back to org.
+	require.NoError(t, field.UnsetRuntimeValue(ctx, userMutator))
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "org-override", val.String())
+	require.Equal(t, runtimeconfig.LevelOrganization, level)
+
+	// Unsetting the org override falls all the way back to the deployment default.
+	require.NoError(t, field.UnsetRuntimeValue(ctx, orgMutator))
+	val, level, err = runtimeconfig.Coalesce(ctx, &field, chain)
+	require.NoError(t, err)
+	require.Equal(t, "deployment-default", val.String())
+	require.Equal(t, runtimeconfig.LevelDeployment, level)
+}
@@ -0,0 +1,44 @@
+package runtimeconfig
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// NotifyMutator wraps a Mutator so that every successful SetRuntimeValue or
+// UnsetRuntimeValue also publishes a change notification on ps, which any Watcher
+// listening on the same pubsub will pick up. The notification carries the key exactly
+// as the wrapped Mutator receives it: beneath NewOrgMutator or a user/workspace mutator
+// that is the scoped key, which Entry.Subscribe (keyed on the entry's own key) does not
+// receive.
+func NotifyMutator(mutator Mutator, ps pubsub.Pubsub) Mutator {
+	return &notifyMutator{Mutator: mutator, ps: ps}
+}
+
+type notifyMutator struct {
+	Mutator
+	ps pubsub.Pubsub
+}
+
+func (m *notifyMutator) UpsertRuntimeConfig(ctx context.Context, key string, val string) error {
+	if err := m.Mutator.UpsertRuntimeConfig(ctx, key, val); err != nil {
+		return err
+	}
+	if err := publish(m.ps, key, val, false); err != nil {
+		return xerrors.Errorf("notify watchers: %w", err)
+	}
+	return nil
+}
+
+func (m *notifyMutator) DeleteRuntimeConfig(ctx context.Context, key string) error {
+	if err := m.Mutator.DeleteRuntimeConfig(ctx, key); err != nil {
+		return err
+	}
+	if err := publish(m.ps, key, "", true); err != nil {
+		return xerrors.Errorf("notify watchers: %w", err)
+	}
+	return nil
+}
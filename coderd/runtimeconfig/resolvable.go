@@ -0,0 +1,87 @@
+package runtimeconfig
+
+import (
+	"context"
+
+	"github.com/coder/serpent"
+)
+
+// Resolvable is the type-erased surface of an Entry[T]. It lets code that doesn't know
+// (or care about) T — like an HTTP handler enumerating every registered deployment
+// option — read and write an entry's runtime value as a plain string.
+type Resolvable interface {
+	// Key returns the key this entry was registered under via SetKey. It's empty if
+	// SetKey hasn't been called yet.
+	Key() string
+	// StartupValueString returns the String() form of the entry's startup
+	// (deployment-wide) value.
+	StartupValueString() string
+	// ResolveString returns the String() form of the org-level (or other resolver-
+	// scoped) override, if one is set.
+	ResolveString(ctx context.Context, r Resolver) (string, error)
+	// SetRuntimeValueString parses raw using the entry's underlying type and, if it
+	// parses, writes it through m. The returned error wraps the parse failure so
+	// callers (e.g. the REST handler) can surface it as a 400.
+	SetRuntimeValueString(ctx context.Context, m Mutator, raw string) error
+	// UnsetRuntimeValue removes the override, if any, falling back to the startup
+	// value on the next resolve.
+	UnsetRuntimeValue(ctx context.Context, m Mutator) error
+	// Validate reports whether raw parses as the entry's underlying type, without
+	// writing anything. It's used to validate an entire import document before any of
+	// it is applied.
+	Validate(raw string) error
+}
+
+// Key returns the key this entry was registered under via SetKey.
+func (e *Entry[T]) Key() string {
+	return e.key
+}
+
+// StartupValueString returns the String() form of the entry's startup value.
+func (e *Entry[T]) StartupValueString() string {
+	return e.StartupValue().String()
+}
+
+// ResolveString returns the String() form of the resolved override, if any.
+func (e *Entry[T]) ResolveString(ctx context.Context, r Resolver) (string, error) {
+	val, err := e.Resolve(ctx, r)
+	if err != nil {
+		return "", err
+	}
+	return val.String(), nil
+}
+
+// SetRuntimeValueString parses raw as a T and writes it through m.
+func (e *Entry[T]) SetRuntimeValueString(ctx context.Context, m Mutator, raw string) error {
+	val, err := parseEntryValue[T](raw)
+	if err != nil {
+		return err
+	}
+	return e.SetRuntimeValue(ctx, m, val)
+}
+
+// Validate reports whether raw parses as a T, without writing anything.
+func (e *Entry[T]) Validate(raw string) error {
+	_, err := parseEntryValue[T](raw)
+	return err
+}
+
+// Discover walks a serpent.OptionSet (typically codersdk.DeploymentValues.Options())
+// and returns every option whose Value is a runtimeconfig.Entry, keyed by Entry.Key().
+// Options that were never wrapped in an Entry (and so can't be overridden at runtime)
+// are skipped. It's used by the runtime-config REST handlers to validate that a
+// requested key exists and to report the deployment-wide startup value alongside any
+// override.
+func Discover(options serpent.OptionSet) map[string]Resolvable {
+	entries := make(map[string]Resolvable)
+	for _, opt := range options {
+		entry, ok := opt.Value.(Resolvable)
+		if !ok {
+			continue
+		}
+		if key := entry.Key(); key != "" {
+			entries[key] = entry
+		}
+	}
+	return entries
+}
@@ -0,0 +1,88 @@
+package runtimeconfig
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Subscription is returned by Entry.Subscribe. Call Unsubscribe to stop receiving
+// callbacks and release the resources backing the subscription.
+type Subscription struct {
+	unsubscribe func()
+}
+
+// Unsubscribe stops delivering callbacks for the Entry this Subscription was created
+// from. It's safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+	s.unsubscribe()
+}
+
+// Subscribe registers fn to be called with the entry's old and new value whenever its
+// runtime value changes, whether the write happened in this process or another
+// replica. Writes that arrive while an earlier one is still waiting to be delivered are
+// coalesced, so fn observes only the latest of them; a write already picked up for
+// delivery before the next one arrives is delivered on its own. fn is called from a
+// dedicated goroutine owned by the returned Subscription, never concurrently with
+// itself.
+//
+// resolver seeds the "current" value Subscribe tracks before the first callback, via
+// Coalesce, so a consumer that subscribes after an override already exists sees that
+// override as old on the next change rather than the deployment default. It should
+// typically be the same resolver (e.g. an org resolver) the consumer would otherwise
+// call Coalesce against directly.
+//
+// SetKey must have been called on e before Subscribe, same as Resolve/SetRuntimeValue.
+func (e *Entry[T]) Subscribe(ctx context.Context, w *Watcher, resolver Resolver, fn func(old, new T)) (*Subscription, error) {
+	if e.key == "" {
+		return nil, ErrKeyNotSet
+	}
+
+	initial, err := e.Coalesce(ctx, resolver)
+	if err != nil {
+		return nil, xerrors.Errorf("read current value of %q to seed subscription: %w", e.key, err)
+	}
+
+	var (
+		mu      sync.Mutex
+		current = initial
+	)
+
+	cancel := w.subscribe(e.key, func(raw string, unset bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next := current
+		if unset {
+			next = e.StartupValue()
+		} else {
+			parsed, err := parseEntryValue[T](raw)
+			if err != nil {
+				// A value that can no longer be parsed (e.g. written by a newer
+				// version of the entry's type) shouldn't crash subscribers; skip it
+				// and wait for the next write.
+				return
+			}
+			next = parsed
+		}
+
+		prev := current
+		current = next
+		fn(prev, next)
+	})
+
+	return &Subscription{unsubscribe: cancel}, nil
+}
+
+// parseEntryValue constructs a fresh T and sets it from raw, mirroring how Resolve
+// parses a value read back from a Resolver.
+func parseEntryValue[T Value](raw string) (T, error) {
+	var zero T
+	val := reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+	if err := val.Set(raw); err != nil {
+		return zero, err
+	}
+	return val, nil
+}
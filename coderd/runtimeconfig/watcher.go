@@ -0,0 +1,216 @@
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// pubsubChangeEvent is the channel name runtimeconfig mutations are published on.
+// A single channel is used for all entries; subscribers filter by Key.
+const pubsubChangeEvent = "runtimeconfig_changed"
+
+// changeNotification is the payload published to pubsubChangeEvent whenever a
+// SetRuntimeValue or UnsetRuntimeValue call commits successfully.
+type changeNotification struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Unset bool   `json:"unset"`
+}
+
+// Watcher delivers callbacks when the runtime value behind a key changes, whether the
+// write happened in this process or another replica. It's backed by pubsub.Pubsub
+// (Postgres LISTEN/NOTIFY in production, an in-memory implementation in tests), so a
+// callback only fires once the underlying write has actually committed.
+//
+// Writes only reach a Watcher if the Mutator that performed them was wrapped with
+// NotifyMutator. MutatorWithAudit does not publish notifications.
+type Watcher struct {
+	ps pubsub.Pubsub
+
+	mu     sync.Mutex
+	subs   map[string]map[uuid.UUID]*subscription
+	cancel func()
+}
+
+// subscription coalesces bursts of writes for a single key: only the most recently
+// published value is ever delivered, identified by monotonically increasing seq so
+// Flush can tell when a particular write has been delivered.
+type subscription struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	closed    bool
+	hasValue  bool
+	raw       string
+	unset     bool
+	seq       uint64
+	processed uint64
+	done      chan struct{}
+}
+
+func newSubscription() *subscription {
+	sub := &subscription{done: make(chan struct{})}
+	sub.cond = sync.NewCond(&sub.mu)
+	return sub
+}
+
+func (s *subscription) publish(raw string, unset bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.raw, s.unset, s.hasValue = raw, unset, true
+	s.seq++
+	s.cond.Broadcast()
+}
+
+func (s *subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.cond.Broadcast()
+	close(s.done)
+}
+
+// flush blocks until every value published before it was called has been delivered.
+func (s *subscription) flush() {
+	s.mu.Lock()
+	target := s.seq
+	defer s.mu.Unlock()
+	for s.processed < target && !s.closed {
+		s.cond.Wait()
+	}
+}
+
+func (s *subscription) run(notify func(raw string, unset bool)) {
+	for {
+		s.mu.Lock()
+		for !s.hasValue && !s.closed {
+			s.cond.Wait()
+		}
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		raw, unset, seq := s.raw, s.unset, s.seq
+		s.hasValue = false
+		s.mu.Unlock()
+
+		notify(raw, unset)
+
+		s.mu.Lock()
+		s.processed = seq
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+// NewWatcher returns a Watcher listening for changes on ps. Callers should keep the
+// returned Watcher around for the lifetime of the subscriptions made against it, and
+// call Close when done.
+func NewWatcher(ps pubsub.Pubsub) (*Watcher, error) {
+	w := &Watcher{
+		ps:   ps,
+		subs: make(map[string]map[uuid.UUID]*subscription),
+	}
+
+	cancel, err := ps.Subscribe(pubsubChangeEvent, w.handle)
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to %s: %w", pubsubChangeEvent, err)
+	}
+	w.cancel = cancel
+
+	return w, nil
+}
+
+// Close stops listening for changes and releases the underlying pubsub subscription.
+func (w *Watcher) Close() error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return nil
+}
+
+func (w *Watcher) handle(_ context.Context, msg []byte) {
+	var changed changeNotification
+	if err := json.Unmarshal(msg, &changed); err != nil {
+		// Malformed messages shouldn't happen, and there's no good way to surface
+		// an error from a pubsub callback, so we just drop it.
+		return
+	}
+
+	w.mu.Lock()
+	subs := make([]*subscription, 0, len(w.subs[changed.Key]))
+	for _, sub := range w.subs[changed.Key] {
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.publish(changed.Value, changed.Unset)
+	}
+}
+
+// subscribe registers notify to be invoked, in order, with the raw string form of the
+// changes published for key. Changes published while an earlier one is still waiting
+// to be delivered replace it, so only the latest of them is delivered. It returns an
+// unsubscribe func.
+func (w *Watcher) subscribe(key string, notify func(raw string, unset bool)) (cancel func()) {
+	sub := newSubscription()
+
+	w.mu.Lock()
+	if w.subs[key] == nil {
+		w.subs[key] = make(map[uuid.UUID]*subscription)
+	}
+	id := uuid.New()
+	w.subs[key][id] = sub
+	w.mu.Unlock()
+
+	go sub.run(notify)
+
+	return func() {
+		w.mu.Lock()
+		delete(w.subs[key], id)
+		if len(w.subs[key]) == 0 {
+			delete(w.subs, key)
+		}
+		w.mu.Unlock()
+		sub.close()
+	}
+}
+
+// Flush blocks until every change notification already published for key has been
+// delivered to every current subscriber. It's intended for tests that need a
+// synchronization point after a SetRuntimeValue/UnsetRuntimeValue call, since delivery
+// to a Watcher is asynchronous.
+func (w *Watcher) Flush(key string) {
+	w.mu.Lock()
+	subs := make([]*subscription, 0, len(w.subs[key]))
+	for _, sub := range w.subs[key] {
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.flush()
+	}
+}
+
+// publish marshals and publishes a change for key on ps. It's called by NotifyMutator
+// after a write commits.
+func publish(ps pubsub.Pubsub, key string, value string, unset bool) error {
+	msg, err := json.Marshal(changeNotification{Key: key, Value: value, Unset: unset})
+	if err != nil {
+		return xerrors.Errorf("marshal change notification: %w", err)
+	}
+	if err := ps.Publish(pubsubChangeEvent, msg); err != nil {
+		return xerrors.Errorf("publish change notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package runtimeconfig_test
+
+import (
+	"testing"
+
+	"github.com/coder/serpent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// TestWatcher demonstrates subscribing to changes made against a runtimeconfig.Entry,
+// including from a mutator other than the one the subscription was set up through.
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	ps := pubsub.NewInMemory()
+	store := dbmem.New()
+	mutator := runtimeconfig.NotifyMutator(runtimeconfig.NewStoreMutator(store), ps)
+
+	watcher, err := runtimeconfig.NewWatcher(ps)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	field := runtimeconfig.MustNew[*serpent.String]("my-watched-field", "system@dev.coder.com")
+	resolver := runtimeconfig.NewStoreResolver(store)
+
+	type change struct{ old, new string }
+	changes := make(chan change, 4)
+	sub, err := field.Subscribe(testutil.Context(t, testutil.WaitShort), watcher, resolver, func(old, new *serpent.String) {
+		changes <- change{old.String(), new.String()}
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	override := serpent.String("dogfood@dev.coder.com")
+	require.NoError(t, field.SetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator, &override))
+	watcher.Flush("my-watched-field")
+
+	got := testutil.TryReceive(testutil.Context(t, testutil.WaitShort), t, changes)
+	require.Equal(t, "system@dev.coder.com", got.old)
+	require.Equal(t, "dogfood@dev.coder.com", got.new)
+
+	require.NoError(t, field.UnsetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator))
+	watcher.Flush("my-watched-field")
+
+	got = testutil.TryReceive(testutil.Context(t, testutil.WaitShort), t, changes)
+	require.Equal(t, "dogfood@dev.coder.com", got.old)
+	require.Equal(t, "system@dev.coder.com", got.new)
+
+	// A burst of writes should coalesce down to the latest value only.
+	for _, v := range []string{"a@dev.coder.com", "b@dev.coder.com", "c@dev.coder.com"} {
+		val := serpent.String(v)
+		require.NoError(t, field.SetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator, &val))
+	}
+	watcher.Flush("my-watched-field")
+
+	got = testutil.TryReceive(testutil.Context(t, testutil.WaitShort), t, changes)
+	require.Equal(t, "c@dev.coder.com", got.new)
+	require.Empty(t, changes)
+
+	sub.Unsubscribe()
+	val := serpent.String("after-unsubscribe@dev.coder.com")
+	require.NoError(t, field.SetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator, &val))
+	watcher.Flush("my-watched-field")
+	require.Empty(t, changes)
+}
+
+// TestSubscribeSeedsExistingOverride demonstrates that Subscribe reports the override
+// already in effect as old on the first callback, not the deployment default, when one
+// existed before Subscribe was called.
+func TestSubscribeSeedsExistingOverride(t *testing.T) {
+	t.Parallel()
+
+	ps := pubsub.NewInMemory()
+	store := dbmem.New()
+	mutator := runtimeconfig.NotifyMutator(runtimeconfig.NewStoreMutator(store), ps)
+	resolver := runtimeconfig.NewStoreResolver(store)
+
+	watcher, err := runtimeconfig.NewWatcher(ps)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	field := runtimeconfig.MustNew[*serpent.String]("my-preexisting-field", "system@dev.coder.com")
+
+	override := serpent.String("dogfood@dev.coder.com")
+	require.NoError(t, field.SetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator, &override))
+	watcher.Flush("my-preexisting-field")
+
+	type change struct{ old, new string }
+	changes := make(chan change, 1)
+	sub, err := field.Subscribe(testutil.Context(t, testutil.WaitShort), watcher, resolver, func(old, new *serpent.String) {
+		changes <- change{old.String(), new.String()}
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	next := serpent.String("2nd-override@dev.coder.com")
+	require.NoError(t, field.SetRuntimeValue(testutil.Context(t, testutil.WaitShort), mutator, &next))
+	watcher.Flush("my-preexisting-field")
+
+	got := testutil.TryReceive(testutil.Context(t, testutil.WaitShort), t, changes)
+	require.Equal(t, "dogfood@dev.coder.com", got.old, "should report the pre-existing override, not the deployment default")
+	require.Equal(t, "2nd-override@dev.coder.com", got.new)
+}
@@ -0,0 +1,48 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRuntimeConfig(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	entries, err := client.ListRuntimeConfig(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries, "at least one deployment option should be registered as a runtimeconfig.Entry")
+
+	key := entries[0].Key
+	require.False(t, entries[0].Overridden)
+
+	_, err = client.GetRuntimeConfig(ctx, owner.OrganizationID, "not-a-real-key")
+	require.Error(t, err)
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, 404, sdkErr.StatusCode())
+
+	// Re-setting the startup value as an override should round-trip and flip
+	// Overridden to true, since an override now exists even though it matches the
+	// startup value.
+	updated, err := client.SetRuntimeConfig(ctx, owner.OrganizationID, key, codersdk.UpdateRuntimeConfigRequest{
+		Value: entries[0].StartupValue,
+	})
+	require.NoError(t, err)
+	require.True(t, updated.Overridden)
+	require.Equal(t, entries[0].StartupValue, updated.Value)
+
+	require.NoError(t, client.DeleteRuntimeConfig(ctx, owner.OrganizationID, key))
+
+	got, err := client.GetRuntimeConfig(ctx, owner.OrganizationID, key)
+	require.NoError(t, err)
+	require.False(t, got.Overridden)
+}
@@ -0,0 +1,176 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// RuntimeConfigEntry describes a single overridable deployment setting: its
+// deployment-wide startup value, and the org-level override currently in effect, if
+// any. Overridden is false when Value equals StartupValue because no override has
+// been set for the organization.
+type RuntimeConfigEntry struct {
+	Key          string `json:"key"`
+	StartupValue string `json:"startup_value"`
+	Value        string `json:"value"`
+	Overridden   bool   `json:"overridden"`
+	// When this is true and the caller lacks readSecret permission on the
+	// organization, Value and StartupValue are "***" instead of the real value.
+	Secret bool `json:"secret"`
+}
+
+// ListRuntimeConfig returns every registered runtime-config key for org, along with its
+// startup value and any org-level override.
+func (c *Client) ListRuntimeConfig(ctx context.Context, org uuid.UUID) ([]RuntimeConfigEntry, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/runtime-config", org), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var entries []RuntimeConfigEntry
+	return entries, json.NewDecoder(res.Body).Decode(&entries)
+}
+
+// GetRuntimeConfig returns the startup value and org-level override (if any) for a
+// single key.
+func (c *Client) GetRuntimeConfig(ctx context.Context, org uuid.UUID, key string) (RuntimeConfigEntry, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/runtime-config/%s", org, key), nil)
+	if err != nil {
+		return RuntimeConfigEntry{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RuntimeConfigEntry{}, ReadBodyAsError(res)
+	}
+
+	var entry RuntimeConfigEntry
+	return entry, json.NewDecoder(res.Body).Decode(&entry)
+}
+
+// UpdateRuntimeConfigRequest is the body of a SetRuntimeConfig call.
+type UpdateRuntimeConfigRequest struct {
+	// Value is parsed using the entry's registered type; an unparsable value results
+	// in a 400 whose message includes the parse error.
+	Value string `json:"value"`
+}
+
+// SetRuntimeConfig sets an org-level override for key.
+func (c *Client) SetRuntimeConfig(ctx context.Context, org uuid.UUID, key string, req UpdateRuntimeConfigRequest) (RuntimeConfigEntry, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/runtime-config/%s", org, key), req)
+	if err != nil {
+		return RuntimeConfigEntry{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RuntimeConfigEntry{}, ReadBodyAsError(res)
+	}
+
+	var entry RuntimeConfigEntry
+	return entry, json.NewDecoder(res.Body).Decode(&entry)
+}
+
+// DeleteRuntimeConfig removes the org-level override for key, reverting it to the
+// deployment-wide startup value.
+func (c *Client) DeleteRuntimeConfig(ctx context.Context, org uuid.UUID, key string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/runtime-config/%s", org, key), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RuntimeConfigExport is the document produced by ExportRuntimeConfig and consumed by
+// ImportRuntimeConfig. It only lists keys that have an org-level override; keys still
+// at their deployment default are omitted.
+type RuntimeConfigExport struct {
+	Version int                      `json:"version"`
+	Entries []RuntimeConfigExportRow `json:"entries"`
+}
+
+// RuntimeConfigExportRow is a single overridden key/value pair within a
+// RuntimeConfigExport.
+type RuntimeConfigExportRow struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ExportRuntimeConfig returns every org-level override for org as a document suitable
+// for ImportRuntimeConfig. Overrides of secret entries are left out.
+func (c *Client) ExportRuntimeConfig(ctx context.Context, org uuid.UUID) (RuntimeConfigExport, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/runtime-config/export", org), nil)
+	if err != nil {
+		return RuntimeConfigExport{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return RuntimeConfigExport{}, ReadBodyAsError(res)
+	}
+
+	var export RuntimeConfigExport
+	return export, json.NewDecoder(res.Body).Decode(&export)
+}
+
+// RuntimeConfigImportAction describes what ImportRuntimeConfig did (or, under DryRun,
+// would do) for a single key.
+type RuntimeConfigImportAction string
+
+const (
+	RuntimeConfigImportActionSet   RuntimeConfigImportAction = "set"
+	RuntimeConfigImportActionUnset RuntimeConfigImportAction = "unset"
+	RuntimeConfigImportActionNoop  RuntimeConfigImportAction = "noop"
+)
+
+// RuntimeConfigImportDiff reports the effect of an import on a single key.
+type RuntimeConfigImportDiff struct {
+	Key    string                    `json:"key"`
+	Before string                    `json:"before"`
+	After  string                    `json:"after"`
+	Action RuntimeConfigImportAction `json:"action"`
+}
+
+// ImportRuntimeConfigRequest is the body of an ImportRuntimeConfig call.
+type ImportRuntimeConfigRequest struct {
+	Export RuntimeConfigExport `json:"export"`
+	// DryRun reports what the import would do without writing anything.
+	DryRun bool `json:"dry_run"`
+	// Merge only touches keys present in Export. Without Merge, any org-level
+	// override of a non-secret entry not present in Export is removed, so those
+	// entries end up matching Export exactly. Secret overrides are never changed
+	// by an import.
+	Merge bool `json:"merge"`
+}
+
+// ImportRuntimeConfig applies req.Export to org. Every entry is validated against its
+// registered type before anything is written, so a single malformed value fails the
+// whole import rather than partially applying it.
+func (c *Client) ImportRuntimeConfig(ctx context.Context, org uuid.UUID, req ImportRuntimeConfigRequest) ([]RuntimeConfigImportDiff, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/runtime-config/import", org), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var diffs []RuntimeConfigImportDiff
+	return diffs, json.NewDecoder(res.Body).Decode(&diffs)
+}